@@ -0,0 +1,71 @@
+// Copyright 2020-2021 InfluxData, Inc. All rights reserved.
+// Use of this source code is governed by MIT
+// license that can be found in the LICENSE file.
+
+// Package errors defines the typed errors that WriteAPI and WriteAPIBlocking
+// return for write failures, so callers can classify a failure (transient vs.
+// permanent, retryable vs. not) with errors.As instead of parsing error
+// strings.
+package errors
+
+import "fmt"
+
+// APIError is the generic error returned for a write failure whose body
+// didn't match one of the more specific errors below. Code and Message are
+// taken from InfluxDB's standard {"code":"...","message":"..."} error body.
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// DatabaseNotFoundError is returned when a write targets a database (1.x) or
+// bucket (2.x) that does not exist on the server.
+type DatabaseNotFoundError struct {
+	Bucket string
+}
+
+func (e *DatabaseNotFoundError) Error() string {
+	return fmt.Sprintf("database not found: %s", e.Bucket)
+}
+
+// PartialWriteError is returned when the server accepted some points of a
+// batch and rejected others. Line is the 1-based index, within the submitted
+// line protocol, of the point that caused the rejection, and Reason is the
+// server-provided explanation. Line is -1 if the server didn't report one.
+type PartialWriteError struct {
+	Line   int
+	Reason string
+}
+
+func (e *PartialWriteError) Error() string {
+	return fmt.Sprintf("partial write: %s", e.Reason)
+}
+
+// PointsBeyondRetentionPolicyError is returned when points were dropped
+// because their timestamp falls outside of the bucket's retention policy.
+// Such points are permanently undeliverable and should not be retried.
+type PointsBeyondRetentionPolicyError struct {
+	Reason string
+}
+
+func (e *PointsBeyondRetentionPolicyError) Error() string {
+	return fmt.Sprintf("points beyond retention policy: %s", e.Reason)
+}
+
+// UnableToParseError is returned when the server could not parse the
+// submitted line protocol. Line and Column locate the offending byte within
+// the submitted batch, and are -1 when the server didn't report them.
+type UnableToParseError struct {
+	Line   int
+	Column int
+	Reason string
+}
+
+func (e *UnableToParseError) Error() string {
+	return fmt.Sprintf("unable to parse: %s", e.Reason)
+}
@@ -0,0 +1,24 @@
+// Copyright 2020-2021 InfluxData, Inc. All rights reserved.
+// Use of this source code is governed by MIT
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Error is returned by Service.Do's callers when the server responds with a
+// non-2xx status, carrying enough of the response to let callers classify
+// the failure (see internal/http.ParseWriteError).
+type Error struct {
+	StatusCode int
+	Body       []byte
+	Header     http.Header
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("server returned status %d: %s", e.StatusCode, string(e.Body))
+}
@@ -0,0 +1,99 @@
+// Copyright 2020-2021 InfluxData, Inc. All rights reserved.
+// Use of this source code is governed by MIT
+// license that can be found in the LICENSE file.
+
+// Package http provides the HTTP transport shared by Client and its
+// sub-APIs.
+package http
+
+import (
+	"encoding/base64"
+	"net/http"
+)
+
+// Service performs HTTP requests against an InfluxDB server, injecting the
+// configured Authorization header into every request. It satisfies
+// oapi-codegen's HttpRequestDoer, so the same Service can be passed to
+// domain.NewClientWithResponses.
+type Service interface {
+	// Do sends req with the configured Authorization header added and
+	// returns the response exactly as (*http.Client).Do would.
+	Do(req *http.Request) (*http.Response, error)
+	// SetAuthorization changes the Authorization header value sent with
+	// every subsequent request, e.g. after Client.Setup obtains a token.
+	SetAuthorization(authorization string)
+	// ServerURL returns the base URL requests are issued against.
+	ServerURL() string
+	// ServerAPIURL returns the base URL of the /api/v2 endpoints.
+	ServerAPIURL() string
+}
+
+// Options holds the HTTP transport configuration shared by Client and its APIs.
+type Options struct {
+	httpClient    *http.Client
+	ownHTTPClient bool
+}
+
+// DefaultOptions returns Options configured with a default *http.Client owned by the library.
+func DefaultOptions() *Options {
+	return &Options{httpClient: new(http.Client), ownHTTPClient: true}
+}
+
+// HTTPClient returns the *http.Client used for requests.
+func (o *Options) HTTPClient() *http.Client {
+	return o.httpClient
+}
+
+// OwnHTTPClient reports whether the HTTPClient was created by the library
+// (and so may be closed by Client.Close) rather than supplied by the caller.
+func (o *Options) OwnHTTPClient() bool {
+	return o.ownHTTPClient
+}
+
+// SetHTTPClient overrides the *http.Client used for requests.
+func (o *Options) SetHTTPClient(client *http.Client) *Options {
+	o.httpClient = client
+	o.ownHTTPClient = false
+	return o
+}
+
+// service implements Service.
+type service struct {
+	serverURL     string
+	authorization string
+	options       *Options
+}
+
+// NewService creates a Service for serverURL, sending authorization with
+// every request, configured with opts. If opts is nil, DefaultOptions are used.
+func NewService(serverURL, authorization string, opts *Options) Service {
+	if opts == nil {
+		opts = DefaultOptions()
+	}
+	return &service{serverURL: serverURL, authorization: authorization, options: opts}
+}
+
+func (s *service) ServerURL() string {
+	return s.serverURL
+}
+
+func (s *service) ServerAPIURL() string {
+	return s.serverURL + "api/v2/"
+}
+
+func (s *service) SetAuthorization(authorization string) {
+	s.authorization = authorization
+}
+
+func (s *service) Do(req *http.Request) (*http.Response, error) {
+	if s.authorization != "" {
+		req.Header.Set("Authorization", s.authorization)
+	}
+	return s.options.httpClient.Do(req)
+}
+
+// BasicAuth returns the base64-encoded "username:password" value for a
+// Basic Authorization header, as used by NewClientV1.
+func BasicAuth(username, password string) string {
+	return base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+}
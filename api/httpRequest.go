@@ -0,0 +1,44 @@
+// Copyright 2020-2021 InfluxData, Inc. All rights reserved.
+// Use of this source code is governed by MIT
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	ihttp "github.com/influxdata/influxdb-client-go/v2/api/http"
+)
+
+// doRequest issues method against path (resolved relative to service's
+// server URL) with params as the query string and body as the request
+// body, through service.Do. A non-2xx response is read in full and
+// returned as *ihttp.Error so callers can classify it; the caller is
+// responsible for closing the returned body on success.
+func doRequest(ctx context.Context, service ihttp.Service, method, path string, params url.Values, body io.Reader) (io.ReadCloser, error) {
+	u := service.ServerURL() + path
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := service.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		respBody, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, &ihttp.Error{StatusCode: resp.StatusCode, Body: respBody, Header: resp.Header}
+	}
+	return resp.Body, nil
+}
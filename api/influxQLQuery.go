@@ -0,0 +1,138 @@
+// Copyright 2020-2021 InfluxData, Inc. All rights reserved.
+// Use of this source code is governed by MIT
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	http2 "github.com/influxdata/influxdb-client-go/v2/api/http"
+)
+
+// InfluxQLResult is the parsed result of a single statement of an InfluxQL
+// query, as returned in the "results" array of InfluxDB's /query response.
+type InfluxQLResult struct {
+	StatementID int               `json:"statement_id"`
+	Series      []InfluxQLSeries  `json:"series"`
+	Messages    []InfluxQLMessage `json:"messages,omitempty"`
+	Error       string            `json:"error,omitempty"`
+}
+
+// InfluxQLSeries holds one series of an InfluxQLResult: its measurement name,
+// the tags identifying it, the column names and the row values in column order.
+type InfluxQLSeries struct {
+	Name    string            `json:"name"`
+	Tags    map[string]string `json:"tags,omitempty"`
+	Columns []string          `json:"columns"`
+	Values  [][]interface{}   `json:"values"`
+	Partial bool              `json:"partial,omitempty"`
+}
+
+// InfluxQLMessage is an informational message InfluxDB attaches to a result,
+// e.g. a warning that the query used a deprecated feature.
+type InfluxQLMessage struct {
+	Level string `json:"level"`
+	Text  string `json:"text"`
+}
+
+// InfluxQLQueryAPI runs read-only InfluxQL queries against a database through
+// InfluxDB's legacy /query endpoint. It is intended for use against InfluxDB
+// 1.8+ servers, or 2.x servers running in 1.x compatibility mode.
+type InfluxQLQueryAPI interface {
+	// Query runs the given InfluxQL query against database (optionally scoped
+	// to retentionPolicy, which may be empty) and returns one InfluxQLResult
+	// per statement in the query.
+	Query(ctx context.Context, query, retentionPolicy string) ([]InfluxQLResult, error)
+	// QueryChunked runs query the same way as Query, but asks the server to
+	// stream the response in chunks of chunkSize points and invokes fn for
+	// every result as it arrives, instead of buffering the whole response in
+	// memory. If chunkSize is zero, chunking is not requested and the server
+	// sends the whole response as a single object.
+	QueryChunked(ctx context.Context, query, retentionPolicy string, chunkSize int, fn func(InfluxQLResult) error) error
+}
+
+// influxQLQueryAPI implements InfluxQLQueryAPI
+type influxQLQueryAPI struct {
+	database string
+	service  http2.Service
+}
+
+// NewInfluxQLQueryAPI creates an InfluxQLQueryAPI for the given database,
+// using service to perform HTTP requests.
+func NewInfluxQLQueryAPI(database string, service http2.Service) InfluxQLQueryAPI {
+	return &influxQLQueryAPI{database: database, service: service}
+}
+
+type influxQLResponse struct {
+	// Error is set instead of Results when the query fails outright (a
+	// syntax error, an unknown database, ...); InfluxDB reports that as
+	// {"error":"..."} with no "results" key at all.
+	Error   string           `json:"error,omitempty"`
+	Results []InfluxQLResult `json:"results"`
+}
+
+func (q *influxQLQueryAPI) queryParams(query, retentionPolicy string, chunkSize int) url.Values {
+	params := url.Values{}
+	params.Set("db", q.database)
+	if retentionPolicy != "" {
+		params.Set("rp", retentionPolicy)
+	}
+	params.Set("q", query)
+	if chunkSize > 0 {
+		params.Set("chunked", "true")
+		params.Set("chunk_size", fmt.Sprintf("%d", chunkSize))
+	}
+	return params
+}
+
+func (q *influxQLQueryAPI) Query(ctx context.Context, query, retentionPolicy string) ([]InfluxQLResult, error) {
+	var results []InfluxQLResult
+	err := q.QueryChunked(ctx, query, retentionPolicy, 0, func(r InfluxQLResult) error {
+		results = append(results, r)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (q *influxQLQueryAPI) QueryChunked(ctx context.Context, query, retentionPolicy string, chunkSize int, fn func(InfluxQLResult) error) error {
+	params := q.queryParams(query, retentionPolicy, chunkSize)
+	body, err := doRequest(ctx, q.service, "POST", "query", params, nil)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	// InfluxDB writes one JSON object per chunk on the wire, both when
+	// chunked=true (one object per chunk_size points) and when chunked is
+	// omitted (a single object holding every result). Decoding with
+	// json.Decoder handles both cases uniformly.
+	dec := json.NewDecoder(bufio.NewReader(body))
+	for {
+		var body influxQLResponse
+		if err := dec.Decode(&body); err != nil {
+			if err.Error() == "EOF" {
+				return nil
+			}
+			return fmt.Errorf("failed to parse InfluxQL response: %w", err)
+		}
+		if body.Error != "" {
+			return fmt.Errorf("influxql: %s", body.Error)
+		}
+		for _, r := range body.Results {
+			if r.Error != "" {
+				return fmt.Errorf("influxql: %s", r.Error)
+			}
+			if err := fn(r); err != nil {
+				return err
+			}
+		}
+	}
+}
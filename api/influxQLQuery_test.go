@@ -0,0 +1,94 @@
+// Copyright 2020-2021 InfluxData, Inc. All rights reserved.
+// Use of this source code is governed by MIT
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// fakeQueryService is a minimal ihttp.Service returning a fixed body for
+// every request, for exercising QueryChunked's streaming decode loop
+// without a real server.
+type fakeQueryService struct {
+	body string
+}
+
+func (s *fakeQueryService) ServerURL() string                     { return "http://fake/" }
+func (s *fakeQueryService) ServerAPIURL() string                  { return "http://fake/api/v2/" }
+func (s *fakeQueryService) SetAuthorization(authorization string) {}
+
+func (s *fakeQueryService) Do(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(strings.NewReader(s.body)),
+		Header:     make(http.Header),
+	}, nil
+}
+
+func TestQueryChunkedReturnsTopLevelError(t *testing.T) {
+	svc := &fakeQueryService{body: `{"error":"database not found: \"telegraf\""}`}
+	q := NewInfluxQLQueryAPI("telegraf", svc)
+
+	err := q.QueryChunked(context.Background(), "SELECT * FROM cpu", "", 0, func(InfluxQLResult) error {
+		t.Fatalf("fn should not be called when the response has a top-level error")
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "database not found") {
+		t.Fatalf("expected the top-level error to be returned, got %v", err)
+	}
+}
+
+func TestQueryChunkedReturnsPerResultError(t *testing.T) {
+	svc := &fakeQueryService{body: `{"results":[{"statement_id":0,"error":"engine: closed"}]}`}
+	q := NewInfluxQLQueryAPI("telegraf", svc)
+
+	err := q.QueryChunked(context.Background(), "SELECT * FROM cpu", "", 0, func(InfluxQLResult) error {
+		t.Fatalf("fn should not be called for a result carrying its own error")
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "engine: closed") {
+		t.Fatalf("expected the per-result error to be returned, got %v", err)
+	}
+}
+
+func TestQueryChunkedDecodesEveryChunkObject(t *testing.T) {
+	// InfluxDB writes one JSON object per chunk on the wire; QueryChunked
+	// must decode each in turn and invoke fn once per result across all of them.
+	svc := &fakeQueryService{body: `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["time","value"],"values":[[0,1]]}]}]}` +
+		`{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["time","value"],"values":[[1,2]],"partial":true}]}]}`}
+	q := NewInfluxQLQueryAPI("telegraf", svc)
+
+	var got []InfluxQLResult
+	err := q.QueryChunked(context.Background(), "SELECT * FROM cpu", "", 1, func(r InfluxQLResult) error {
+		got = append(got, r)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("QueryChunked: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected fn to be invoked once per chunk, got %d calls", len(got))
+	}
+	if !got[1].Series[0].Partial {
+		t.Fatalf("expected the second chunk's series to carry partial=true")
+	}
+}
+
+func TestQueryRunsQueryChunkedWithoutChunking(t *testing.T) {
+	svc := &fakeQueryService{body: `{"results":[{"statement_id":0},{"statement_id":1}]}`}
+	q := NewInfluxQLQueryAPI("telegraf", svc)
+
+	results, err := q.Query(context.Background(), "SELECT * FROM cpu; SELECT * FROM mem", "")
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected one InfluxQLResult per statement, got %d", len(results))
+	}
+}
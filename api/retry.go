@@ -0,0 +1,230 @@
+// Copyright 2020-2021 InfluxData, Inc. All rights reserved.
+// Use of this source code is governed by MIT
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned on WriteAPI's errors channel, instead of
+// performing an HTTP call, whenever the configured CircuitBreaker has
+// tripped and is refusing writes.
+var ErrCircuitOpen = errors.New("influxdb2: write circuit breaker is open")
+
+// RetryPolicy decides whether and after how long a failed write should be
+// retried. It is consulted by WriteAPIImpl after every failed HTTP call.
+// attempt is 1 for the first retry. resp is nil when the failure was a
+// transport error rather than an HTTP response. A RetryPolicy implementation
+// must be safe for concurrent use.
+type RetryPolicy interface {
+	// NextBackoff returns how long to wait before retrying attempt, and
+	// whether the write should be retried at all.
+	NextBackoff(attempt int, err error, resp *http.Response) (time.Duration, bool)
+}
+
+// CircuitBreaker is consulted by WriteAPIImpl before every write attempt so
+// that a persistently unhealthy server stops receiving retry traffic instead
+// of being hammered by an ever-growing backlog. Implementations must be safe
+// for concurrent use.
+type CircuitBreaker interface {
+	// Allow reports whether a write attempt may proceed.
+	Allow() bool
+	// RecordSuccess is called after a write attempt succeeds.
+	RecordSuccess()
+	// RecordFailure is called after a write attempt fails.
+	RecordFailure(err error)
+}
+
+// ExponentialJitterRetryPolicy retries with exponentially growing, jittered
+// backoff, honoring a Retry-After response header when the server sends one.
+// It is the default RetryPolicy.
+type ExponentialJitterRetryPolicy struct {
+	// MaxRetries is the maximum number of retry attempts before giving up.
+	MaxRetries int
+	// MaxRetryInterval caps the computed backoff, Retry-After included.
+	MaxRetryInterval time.Duration
+	// BaseInterval is the upper bound of the jittered backoff for the first
+	// retry; the bound doubles with every further attempt.
+	BaseInterval time.Duration
+}
+
+// NewExponentialJitterRetryPolicy creates an ExponentialJitterRetryPolicy
+// with the client's default retry parameters: 5 retries, a 1s base interval
+// and a 125s cap.
+func NewExponentialJitterRetryPolicy() *ExponentialJitterRetryPolicy {
+	return &ExponentialJitterRetryPolicy{
+		MaxRetries:       5,
+		MaxRetryInterval: 125 * time.Second,
+		BaseInterval:     time.Second,
+	}
+}
+
+// NextBackoff implements RetryPolicy.
+func (p *ExponentialJitterRetryPolicy) NextBackoff(attempt int, err error, resp *http.Response) (time.Duration, bool) {
+	if attempt > p.MaxRetries {
+		return 0, false
+	}
+	if resp != nil && !isRetryableStatus(resp.StatusCode) {
+		return 0, false
+	}
+	if resp != nil {
+		if d, ok := retryAfter(resp); ok {
+			return capDuration(d, p.MaxRetryInterval), true
+		}
+	}
+	backoff := p.BaseInterval << uint(attempt-1)
+	if backoff <= 0 || backoff > p.MaxRetryInterval {
+		backoff = p.MaxRetryInterval
+	}
+	jitter := time.Duration(rand.Int63n(int64(backoff) + 1))
+	return capDuration(jitter, p.MaxRetryInterval), true
+}
+
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code == http.StatusServiceUnavailable || code >= 500
+}
+
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	secs, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(secs) * time.Second, true
+}
+
+func capDuration(d, max time.Duration) time.Duration {
+	if d > max {
+		return max
+	}
+	return d
+}
+
+// TokenBucketRetryPolicy limits retries to a maximum number of bytes per
+// second, so that a batch of failed writes against an unhealthy downstream
+// doesn't turn into a retry storm. Writes beyond the configured rate are not
+// retried. The policy keeps a single token bucket: every WriteAPI sharing
+// the policy draws on the same budget, whichever InfluxDB bucket it writes to.
+type TokenBucketRetryPolicy struct {
+	// BytesPerSecond is the sustained retry rate.
+	BytesPerSecond int64
+	// BurstBytes is the capacity of the token bucket: the most bytes that
+	// can accumulate while no retries happen, and so the most that
+	// back-to-back retries can consume before they are refused.
+	BurstBytes int64
+
+	mu       sync.Mutex
+	tokens   int64
+	lastFill time.Time
+}
+
+// NewTokenBucketRetryPolicy creates a TokenBucketRetryPolicy capped at
+// bytesPerSecond, with a burst allowance equal to one second worth of bytes.
+func NewTokenBucketRetryPolicy(bytesPerSecond int64) *TokenBucketRetryPolicy {
+	return &TokenBucketRetryPolicy{
+		BytesPerSecond: bytesPerSecond,
+		BurstBytes:     bytesPerSecond,
+		tokens:         bytesPerSecond,
+	}
+}
+
+// NextBackoff implements RetryPolicy. err's approximate size in bytes is not
+// known to the policy, so each retry attempt is charged a flat cost of 1024
+// bytes against the bucket; once the bucket is empty, retries are refused
+// until it refills.
+func (p *TokenBucketRetryPolicy) NextBackoff(attempt int, err error, resp *http.Response) (time.Duration, bool) {
+	const attemptCost = 1024
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	if p.lastFill.IsZero() {
+		p.lastFill = now
+	}
+	elapsed := now.Sub(p.lastFill)
+	if elapsed > 0 {
+		p.tokens += int64(elapsed.Seconds() * float64(p.BytesPerSecond))
+		if p.tokens > p.BurstBytes {
+			p.tokens = p.BurstBytes
+		}
+		p.lastFill = now
+	}
+	if p.tokens < attemptCost {
+		return 0, false
+	}
+	p.tokens -= attemptCost
+	return time.Duration(attempt) * 100 * time.Millisecond, true
+}
+
+// ConsecutiveFailureCircuitBreaker is the default CircuitBreaker. It opens
+// after FailureThreshold consecutive write failures and stays open for
+// OpenDuration before allowing a single probe write through; the breaker
+// closes again on the probe's success and re-opens on its failure.
+type ConsecutiveFailureCircuitBreaker struct {
+	// FailureThreshold is the number of consecutive failures that trips the breaker.
+	FailureThreshold int
+	// OpenDuration is how long the breaker stays open before probing again.
+	OpenDuration time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// NewConsecutiveFailureCircuitBreaker creates a ConsecutiveFailureCircuitBreaker
+// that opens after 5 consecutive failures and probes again after 30s.
+func NewConsecutiveFailureCircuitBreaker() *ConsecutiveFailureCircuitBreaker {
+	return &ConsecutiveFailureCircuitBreaker{
+		FailureThreshold: 5,
+		OpenDuration:     30 * time.Second,
+	}
+}
+
+// Allow implements CircuitBreaker. Once OpenDuration has elapsed, exactly one
+// caller is let through as a probe; every other concurrent caller keeps
+// seeing the breaker as open until that probe's RecordSuccess/RecordFailure
+// resolves it.
+func (b *ConsecutiveFailureCircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.FailureThreshold {
+		return true
+	}
+	if time.Now().Before(b.openUntil) {
+		return false
+	}
+	if b.probing {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// RecordSuccess implements CircuitBreaker.
+func (b *ConsecutiveFailureCircuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.probing = false
+}
+
+// RecordFailure implements CircuitBreaker.
+func (b *ConsecutiveFailureCircuitBreaker) RecordFailure(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.probing = false
+	if b.failures >= b.FailureThreshold {
+		b.openUntil = time.Now().Add(b.OpenDuration)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2020-2021 InfluxData, Inc. All rights reserved.
+// Use of this source code is governed by MIT
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestExponentialJitterRetryPolicyHonorsRetryAfter(t *testing.T) {
+	p := NewExponentialJitterRetryPolicy()
+	resp := &http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Header:     http.Header{"Retry-After": []string{"2"}},
+	}
+	backoff, retry := p.NextBackoff(1, errors.New("unavailable"), resp)
+	if !retry {
+		t.Fatalf("expected retry to be true")
+	}
+	if backoff != 2*time.Second {
+		t.Fatalf("expected backoff of 2s from Retry-After, got %v", backoff)
+	}
+}
+
+func TestExponentialJitterRetryPolicyStopsAtMaxRetries(t *testing.T) {
+	p := NewExponentialJitterRetryPolicy()
+	if _, retry := p.NextBackoff(p.MaxRetries+1, errors.New("boom"), nil); retry {
+		t.Fatalf("expected retry to be false once attempt exceeds MaxRetries")
+	}
+}
+
+func TestExponentialJitterRetryPolicyDoesNotRetryNonRetryableStatus(t *testing.T) {
+	p := NewExponentialJitterRetryPolicy()
+	resp := &http.Response{StatusCode: http.StatusBadRequest}
+	if _, retry := p.NextBackoff(1, errors.New("bad request"), resp); retry {
+		t.Fatalf("expected retry to be false for a 400 response")
+	}
+}
+
+func TestTokenBucketRetryPolicyRefusesOnceDrained(t *testing.T) {
+	p := NewTokenBucketRetryPolicy(1024)
+	var allowed int
+	for i := 1; i <= 5; i++ {
+		if _, retry := p.NextBackoff(i, errors.New("boom"), nil); retry {
+			allowed++
+		}
+	}
+	if allowed != 1 {
+		t.Fatalf("expected exactly 1 retry to be allowed from a 1024 byte bucket at 1024 bytes/s charging 1024/attempt, got %d", allowed)
+	}
+}
+
+func TestConsecutiveFailureCircuitBreakerOpensAndRecovers(t *testing.T) {
+	b := NewConsecutiveFailureCircuitBreaker()
+	b.FailureThreshold = 3
+	b.OpenDuration = time.Hour
+
+	for i := 0; i < 3; i++ {
+		if !b.Allow() {
+			t.Fatalf("breaker should still allow attempt %d before threshold is hit", i)
+		}
+		b.RecordFailure(errors.New("boom"))
+	}
+	if b.Allow() {
+		t.Fatalf("breaker should be open after FailureThreshold consecutive failures")
+	}
+	b.RecordSuccess()
+	if !b.Allow() {
+		t.Fatalf("breaker should close again after RecordSuccess resets the failure count")
+	}
+}
+
+func TestConsecutiveFailureCircuitBreakerAllowsOnlyOneProbeOnceOpen(t *testing.T) {
+	b := NewConsecutiveFailureCircuitBreaker()
+	b.FailureThreshold = 1
+	b.OpenDuration = -time.Second // already elapsed, so the next Allow is the probe window
+
+	b.RecordFailure(errors.New("boom"))
+
+	if !b.Allow() {
+		t.Fatalf("expected exactly one probe caller to be let through once OpenDuration has elapsed")
+	}
+	for i := 0; i < 5; i++ {
+		if b.Allow() {
+			t.Fatalf("expected every other concurrent caller to be refused while the probe is unresolved")
+		}
+	}
+
+	// The failed probe re-opens the breaker for OpenDuration, so give it one
+	// that has not already elapsed.
+	b.OpenDuration = time.Hour
+	b.RecordFailure(errors.New("probe failed too"))
+	if b.Allow() {
+		t.Fatalf("expected the breaker to stay open after the probe itself failed")
+	}
+}
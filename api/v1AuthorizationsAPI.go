@@ -0,0 +1,145 @@
+// Copyright 2020-2021 InfluxData, Inc. All rights reserved.
+// Use of this source code is governed by MIT
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/influxdb-client-go/v2/domain"
+)
+
+// V1Authorization maps a legacy InfluxDB 1.x username/password credential to
+// a set of 2.x bucket permissions, as served by InfluxDB's
+// /private/legacy/authorizations endpoints.
+type V1Authorization struct {
+	ID          *string                                   `json:"id,omitempty"`
+	Username    string                                    `json:"username,omitempty"`
+	OrgID       string                                    `json:"orgID"`
+	Description string                                    `json:"description,omitempty"`
+	Status      *domain.AuthorizationUpdateRequestStatus `json:"status,omitempty"`
+	Permissions []domain.Permission                      `json:"permissions"`
+}
+
+// V1AuthorizationsAPI manages InfluxDB 1.x-compatible (username/password)
+// authorizations on a 2.x server, so clients migrating from 1.x can
+// provision the legacy credentials that Telegraf, Grafana and Chronograf
+// still authenticate with.
+type V1AuthorizationsAPI interface {
+	// CreateAuthorization creates a new v1 authorization.
+	CreateAuthorization(ctx context.Context, auth *V1Authorization) (*V1Authorization, error)
+	// FindAuthorizationsByUser returns all v1 authorizations for the given username.
+	FindAuthorizationsByUser(ctx context.Context, username string) ([]V1Authorization, error)
+	// SetPassword sets the password for the v1 authorization identified by authID.
+	SetPassword(ctx context.Context, authID, password string) error
+	// UpdateAuthorizationStatus sets the v1 authorization identified by authID
+	// to status ("active" or "inactive").
+	UpdateAuthorizationStatus(ctx context.Context, authID, status string) (*V1Authorization, error)
+	// DeleteAuthorization removes the v1 authorization identified by authID.
+	DeleteAuthorization(ctx context.Context, authID string) error
+}
+
+// v1AuthorizationsAPI implements V1AuthorizationsAPI
+type v1AuthorizationsAPI struct {
+	apiClient *domain.ClientWithResponses
+}
+
+// NewV1AuthorizationsAPI creates a V1AuthorizationsAPI backed by apiClient.
+func NewV1AuthorizationsAPI(apiClient *domain.ClientWithResponses) V1AuthorizationsAPI {
+	return &v1AuthorizationsAPI{apiClient: apiClient}
+}
+
+func (a *v1AuthorizationsAPI) CreateAuthorization(ctx context.Context, auth *V1Authorization) (*V1Authorization, error) {
+	body := domain.PostPrivateLegacyAuthorizationsJSONRequestBody{
+		Description: &auth.Description,
+		OrgID:       auth.OrgID,
+		Permissions: auth.Permissions,
+		Status:      auth.Status,
+		Username:    &auth.Username,
+	}
+	response, err := a.apiClient.PostPrivateLegacyAuthorizationsWithResponse(ctx, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create v1 authorization: %w", err)
+	}
+	if response.JSONDefault != nil {
+		return nil, domain.DomainErrorToError(response.JSONDefault, response.StatusCode())
+	}
+	return fromLegacyAuthorization(response.JSON201), nil
+}
+
+func (a *v1AuthorizationsAPI) FindAuthorizationsByUser(ctx context.Context, username string) ([]V1Authorization, error) {
+	params := &domain.GetPrivateLegacyAuthorizationsParams{User: &username}
+	response, err := a.apiClient.GetPrivateLegacyAuthorizationsWithResponse(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find v1 authorizations for user %s: %w", username, err)
+	}
+	if response.JSONDefault != nil {
+		return nil, domain.DomainErrorToError(response.JSONDefault, response.StatusCode())
+	}
+	var authorizations []V1Authorization
+	if response.JSON200 != nil {
+		for _, a := range *response.JSON200.Authorizations {
+			authorizations = append(authorizations, *fromLegacyAuthorization(&a))
+		}
+	}
+	return authorizations, nil
+}
+
+func (a *v1AuthorizationsAPI) SetPassword(ctx context.Context, authID, password string) error {
+	body := domain.PostPrivateLegacyAuthorizationsIDPasswordJSONRequestBody{Password: password}
+	response, err := a.apiClient.PostPrivateLegacyAuthorizationsIDPasswordWithResponse(ctx, authID, body)
+	if err != nil {
+		return fmt.Errorf("failed to set password for v1 authorization %s: %w", authID, err)
+	}
+	if response.JSONDefault != nil {
+		return domain.DomainErrorToError(response.JSONDefault, response.StatusCode())
+	}
+	return nil
+}
+
+func (a *v1AuthorizationsAPI) UpdateAuthorizationStatus(ctx context.Context, authID, status string) (*V1Authorization, error) {
+	authStatus := domain.AuthorizationUpdateRequestStatus(status)
+	body := domain.PatchPrivateLegacyAuthorizationsIDJSONRequestBody{Status: &authStatus}
+	response, err := a.apiClient.PatchPrivateLegacyAuthorizationsIDWithResponse(ctx, authID, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update status of v1 authorization %s: %w", authID, err)
+	}
+	if response.JSONDefault != nil {
+		return nil, domain.DomainErrorToError(response.JSONDefault, response.StatusCode())
+	}
+	return fromLegacyAuthorization(response.JSON200), nil
+}
+
+func (a *v1AuthorizationsAPI) DeleteAuthorization(ctx context.Context, authID string) error {
+	response, err := a.apiClient.DeletePrivateLegacyAuthorizationsIDWithResponse(ctx, authID)
+	if err != nil {
+		return fmt.Errorf("failed to delete v1 authorization %s: %w", authID, err)
+	}
+	if response.JSONDefault != nil {
+		return domain.DomainErrorToError(response.JSONDefault, response.StatusCode())
+	}
+	return nil
+}
+
+// fromLegacyAuthorization converts the generated domain.LegacyAuthorization
+// wire type into the V1Authorization this API exposes.
+func fromLegacyAuthorization(la *domain.LegacyAuthorization) *V1Authorization {
+	if la == nil {
+		return nil
+	}
+	v1 := &V1Authorization{
+		ID:          la.Id,
+		OrgID:       *la.OrgID,
+		Status:      la.Status,
+		Permissions: *la.Permissions,
+	}
+	if la.Description != nil {
+		v1.Description = *la.Description
+	}
+	if la.Username != nil {
+		v1.Username = *la.Username
+	}
+	return v1
+}
@@ -0,0 +1,70 @@
+// Copyright 2020-2021 InfluxData, Inc. All rights reserved.
+// Use of this source code is governed by MIT
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb-client-go/v2/domain"
+)
+
+func TestFromLegacyAuthorizationReturnsNilForNil(t *testing.T) {
+	if got := fromLegacyAuthorization(nil); got != nil {
+		t.Fatalf("expected nil, got %+v", got)
+	}
+}
+
+func TestFromLegacyAuthorizationMapsFields(t *testing.T) {
+	id := "1234"
+	orgID := "my-org-id"
+	description := "telegraf"
+	username := "telegraf-agent"
+	status := domain.AuthorizationUpdateRequestStatus("active")
+	permissions := []domain.Permission{}
+	la := &domain.LegacyAuthorization{
+		Id:          &id,
+		OrgID:       &orgID,
+		Description: &description,
+		Username:    &username,
+		Status:      &status,
+		Permissions: &permissions,
+	}
+
+	got := fromLegacyAuthorization(la)
+
+	if got.ID == nil || *got.ID != id {
+		t.Fatalf("expected ID %q, got %v", id, got.ID)
+	}
+	if got.OrgID != orgID {
+		t.Fatalf("expected OrgID %q, got %q", orgID, got.OrgID)
+	}
+	if got.Description != description {
+		t.Fatalf("expected Description %q, got %q", description, got.Description)
+	}
+	if got.Username != username {
+		t.Fatalf("expected Username %q, got %q", username, got.Username)
+	}
+	if got.Status == nil || *got.Status != status {
+		t.Fatalf("expected Status %q, got %v", status, got.Status)
+	}
+}
+
+func TestFromLegacyAuthorizationLeavesOptionalFieldsEmptyWhenNil(t *testing.T) {
+	orgID := "my-org-id"
+	permissions := []domain.Permission{}
+	la := &domain.LegacyAuthorization{
+		OrgID:       &orgID,
+		Permissions: &permissions,
+	}
+
+	got := fromLegacyAuthorization(la)
+
+	if got.Description != "" {
+		t.Fatalf("expected empty Description, got %q", got.Description)
+	}
+	if got.Username != "" {
+		t.Fatalf("expected empty Username, got %q", got.Username)
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright 2020-2021 InfluxData, Inc. All rights reserved.
+// Use of this source code is governed by MIT
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	ihttp "github.com/influxdata/influxdb-client-go/v2/api/http"
+	writeerr "github.com/influxdata/influxdb-client-go/v2/internal/http"
+)
+
+// WriteOptions holds the configuration for WriteAPI and WriteAPIBlocking.
+type WriteOptions struct {
+	batchSize      uint
+	retryPolicy    RetryPolicy
+	circuitBreaker CircuitBreaker
+}
+
+// DefaultWriteOptions returns WriteOptions with the client's defaults: a
+// batch size of 5000 points, an ExponentialJitterRetryPolicy and no
+// CircuitBreaker.
+func DefaultWriteOptions() *WriteOptions {
+	return &WriteOptions{
+		batchSize:   5000,
+		retryPolicy: NewExponentialJitterRetryPolicy(),
+	}
+}
+
+// BatchSize returns the maximum number of points held in memory before a batch is written.
+func (o *WriteOptions) BatchSize() uint {
+	return o.batchSize
+}
+
+// SetBatchSize sets the maximum number of points held in memory before a batch is written.
+func (o *WriteOptions) SetBatchSize(batchSize uint) *WriteOptions {
+	o.batchSize = batchSize
+	return o
+}
+
+// RetryPolicy returns the policy WriteAPIImpl consults to decide whether and
+// when to retry a failed write. DefaultWriteOptions sets it to an
+// ExponentialJitterRetryPolicy; it is nil when retries were disabled with
+// SetRetryPolicy(nil).
+func (o *WriteOptions) RetryPolicy() RetryPolicy {
+	return o.retryPolicy
+}
+
+// SetRetryPolicy sets the policy WriteAPIImpl consults to decide whether and
+// when to retry a failed write. A nil policy disables retries.
+func (o *WriteOptions) SetRetryPolicy(retryPolicy RetryPolicy) *WriteOptions {
+	o.retryPolicy = retryPolicy
+	return o
+}
+
+// CircuitBreaker returns the breaker WriteAPIImpl consults before every
+// write attempt, or nil if none is configured.
+func (o *WriteOptions) CircuitBreaker() CircuitBreaker {
+	return o.circuitBreaker
+}
+
+// SetCircuitBreaker sets the breaker WriteAPIImpl consults before every
+// write attempt.
+func (o *WriteOptions) SetCircuitBreaker(circuitBreaker CircuitBreaker) *WriteOptions {
+	o.circuitBreaker = circuitBreaker
+	return o
+}
+
+// WriteAPI provides asynchronous, non-blocking, methods for writing time series data.
+type WriteAPI interface {
+	// WriteRecord writes line protocol record(s) asynchronously.
+	WriteRecord(line ...string)
+	// Flush forces all pending writes to be sent.
+	Flush()
+	// Errors returns a channel for reading errors which occur during async writes.
+	// Errors already classified by api/errors (e.g. *errors.PartialWriteError)
+	// as well as ErrCircuitOpen can be read from this channel.
+	Errors() <-chan error
+	// WriteStream opens a long-lived write stream for raw line-protocol
+	// bytes, for callers who already hold line protocol and want to bypass
+	// the per-record path. See WriteAPIImpl.WriteStream.
+	WriteStream(ctx context.Context) (io.WriteCloser, error)
+	// Close finishes outstanding writes and closes the Errors channel.
+	Close()
+}
+
+// writeAPIQueueSize is the number of pending WriteRecord calls buffered
+// between the caller and the background batching goroutine before
+// WriteRecord starts to block.
+const writeAPIQueueSize = 1000
+
+// writer holds the state needed to turn raw line protocol into an HTTP
+// write, including the CircuitBreaker/RetryPolicy consultation in writeRaw.
+// It is shared by WriteAPIImpl, whose background goroutine calls writeRaw
+// for each batch, and WriteAPIBlockingImpl, which calls it directly per
+// record, without either one needing a goroutine it doesn't use.
+type writer struct {
+	org, bucket  string
+	service      ihttp.Service
+	writeOptions *WriteOptions
+}
+
+// WriteAPIImpl implements WriteAPI. Records passed to WriteRecord are handed
+// off to a background goroutine that accumulates them into a batch and
+// writes the batch once it holds at least WriteOptions.BatchSize records,
+// Flush is called, or Close drains what remains.
+type WriteAPIImpl struct {
+	*writer
+	errCh chan error
+
+	lineCh    chan string
+	flushCh   chan chan struct{}
+	doneCh    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewWriteAPI returns a new WriteAPIImpl writing to the given org and bucket
+// through service, configured with writeOptions. If writeOptions is nil,
+// DefaultWriteOptions are used. The returned WriteAPIImpl owns a background
+// goroutine that must be released by calling Close.
+func NewWriteAPI(org, bucket string, service ihttp.Service, writeOptions *WriteOptions) *WriteAPIImpl {
+	if writeOptions == nil {
+		writeOptions = DefaultWriteOptions()
+	}
+	w := &WriteAPIImpl{
+		writer:  &writer{org: org, bucket: bucket, service: service, writeOptions: writeOptions},
+		errCh:   make(chan error, 10),
+		lineCh:  make(chan string, writeAPIQueueSize),
+		flushCh: make(chan chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// WriteRecord implements WriteAPI. It hands the record to the background
+// batching goroutine and returns without waiting for it to be sent.
+func (w *WriteAPIImpl) WriteRecord(line ...string) {
+	if len(line) == 0 {
+		return
+	}
+	data := strings.Join(line, "\n") + "\n"
+	select {
+	case w.lineCh <- data:
+	case <-w.doneCh:
+	}
+}
+
+// Flush implements WriteAPI. It blocks until the batching goroutine has
+// written everything accumulated so far.
+func (w *WriteAPIImpl) Flush() {
+	ack := make(chan struct{})
+	select {
+	case w.flushCh <- ack:
+		<-ack
+	case <-w.doneCh:
+	}
+}
+
+// Errors implements WriteAPI.
+func (w *WriteAPIImpl) Errors() <-chan error {
+	return w.errCh
+}
+
+// Close implements WriteAPI. It stops accepting new records, waits for the
+// batching goroutine to write whatever is left, then closes the Errors
+// channel. Close is safe to call more than once.
+func (w *WriteAPIImpl) Close() {
+	w.closeOnce.Do(func() {
+		close(w.lineCh)
+		<-w.doneCh
+		close(w.errCh)
+	})
+}
+
+// run is the background batching goroutine started by NewWriteAPI. It
+// accumulates records written to lineCh into batch, writing it out once
+// WriteOptions.BatchSize records have accumulated, a Flush is requested, or
+// lineCh is closed by Close.
+func (w *WriteAPIImpl) run() {
+	defer close(w.doneCh)
+	var batch strings.Builder
+	var batched uint
+	flush := func() {
+		if batched == 0 {
+			return
+		}
+		if err := w.writeRaw(context.Background(), []byte(batch.String())); err != nil {
+			w.emitError(err)
+		}
+		batch.Reset()
+		batched = 0
+	}
+	for {
+		select {
+		case line, ok := <-w.lineCh:
+			if !ok {
+				flush()
+				return
+			}
+			batch.WriteString(line)
+			batched += uint(strings.Count(line, "\n"))
+			if batchSize := w.writeOptions.BatchSize(); batchSize > 0 && batched >= batchSize {
+				flush()
+			}
+		case ack := <-w.flushCh:
+			flush()
+			close(ack)
+		}
+	}
+}
+
+func (w *WriteAPIImpl) emitError(err error) {
+	select {
+	case w.errCh <- err:
+	default:
+	}
+}
+
+// writeRaw sends data, raw line protocol, to the server. It consults the
+// configured CircuitBreaker before every attempt, surfacing ErrCircuitOpen
+// without performing an HTTP call while the breaker is open, and consults
+// the configured RetryPolicy after every failed attempt to decide whether
+// and how long to wait before retrying.
+func (w *writer) writeRaw(ctx context.Context, data []byte) error {
+	breaker := w.writeOptions.CircuitBreaker()
+	policy := w.writeOptions.RetryPolicy()
+
+	params := url.Values{}
+	params.Set("org", w.org)
+	params.Set("bucket", w.bucket)
+	params.Set("precision", "ns")
+
+	for attempt := 0; ; {
+		if breaker != nil && !breaker.Allow() {
+			return ErrCircuitOpen
+		}
+		body, err := doRequest(ctx, w.service, "POST", "write", params, bytes.NewReader(data))
+		if err == nil {
+			body.Close()
+			if breaker != nil {
+				breaker.RecordSuccess()
+			}
+			return nil
+		}
+		classified := classifyWriteError(w.bucket, err)
+		if breaker != nil {
+			breaker.RecordFailure(classified)
+		}
+		if policy == nil {
+			return classified
+		}
+		attempt++
+		backoff, retry := policy.NextBackoff(attempt, classified, httpResponseOf(err))
+		if !retry {
+			return classified
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// httpResponseOf extracts the *http.Response carried by a *ihttp.Error, or
+// nil when err is a transport-level failure with no response to inspect.
+func httpResponseOf(err error) *http.Response {
+	httpErr, ok := err.(*ihttp.Error)
+	if !ok {
+		return nil
+	}
+	return &http.Response{StatusCode: httpErr.StatusCode, Header: httpErr.Header}
+}
+
+// classifyWriteError turns the opaque error returned by the HTTP service
+// into one of the typed errors in api/errors (DatabaseNotFoundError,
+// PartialWriteError, PointsBeyondRetentionPolicyError, UnableToParseError,
+// or the generic APIError), so callers can errors.As against the concrete
+// failure instead of parsing error strings. bucket is attached to
+// DatabaseNotFoundError.
+func classifyWriteError(bucket string, err error) error {
+	httpErr, ok := err.(*ihttp.Error)
+	if !ok {
+		return err
+	}
+	return writeerr.ParseWriteError(httpErr.StatusCode, bucket, httpErr.Body)
+}
@@ -0,0 +1,51 @@
+// Copyright 2020-2021 InfluxData, Inc. All rights reserved.
+// Use of this source code is governed by MIT
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	ihttp "github.com/influxdata/influxdb-client-go/v2/api/http"
+)
+
+// WriteAPIBlocking provides synchronous, blocking, methods for writing time series data.
+type WriteAPIBlocking interface {
+	// WriteRecord writes line protocol record(s) synchronously, blocking
+	// until the write succeeds or permanently fails, applying the same
+	// RetryPolicy/CircuitBreaker and error classification as WriteAPI.
+	WriteRecord(ctx context.Context, line ...string) error
+	// WriteReader reads line protocol from r until EOF and writes it to the
+	// server, blocking until every point has been acknowledged. See
+	// WriteAPIBlockingImpl.WriteReader.
+	WriteReader(ctx context.Context, r io.Reader) error
+}
+
+// WriteAPIBlockingImpl implements WriteAPIBlocking. It shares writer's
+// retry/circuit-breaker/error-classification write path with WriteAPIImpl,
+// but calls it directly per record instead of through a background
+// goroutine, since every call already blocks until the write settles.
+type WriteAPIBlockingImpl struct {
+	*writer
+}
+
+// NewWriteAPIBlocking returns a new WriteAPIBlockingImpl writing to the
+// given org and bucket through service, configured with writeOptions.
+func NewWriteAPIBlocking(org, bucket string, service ihttp.Service, writeOptions *WriteOptions) *WriteAPIBlockingImpl {
+	if writeOptions == nil {
+		writeOptions = DefaultWriteOptions()
+	}
+	return &WriteAPIBlockingImpl{writer: &writer{org: org, bucket: bucket, service: service, writeOptions: writeOptions}}
+}
+
+// WriteRecord implements WriteAPIBlocking.
+func (w *WriteAPIBlockingImpl) WriteRecord(ctx context.Context, line ...string) error {
+	if len(line) == 0 {
+		return nil
+	}
+	data := []byte(strings.Join(line, "\n") + "\n")
+	return w.writeRaw(ctx, data)
+}
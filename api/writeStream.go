@@ -0,0 +1,128 @@
+// Copyright 2020-2021 InfluxData, Inc. All rights reserved.
+// Use of this source code is governed by MIT
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"io"
+)
+
+// DefaultWriteStreamByteThreshold is the number of raw line-protocol bytes
+// WriteStream buffers before rotating to a new underlying HTTP request.
+const DefaultWriteStreamByteThreshold = 10 * 1024 * 1024
+
+// writeStream is the io.WriteCloser returned by WriteAPIImpl.WriteStream. It
+// lets callers who already hold line-protocol bytes push them straight to
+// the server, bypassing the per-record channel/batching path that
+// WriteRecord uses.
+//
+// writeStream buffers the bytes and sends them whenever the configured byte
+// or line-count threshold is hit. Each flushed chunk is sent as its own
+// uncompressed write request; the stream does not hold one HTTP request open
+// and does not gzip the body. It reuses WriteAPIImpl's retry/error
+// classification (RetryPolicy, CircuitBreaker, api/errors) for every chunk
+// it flushes.
+type writeStream struct {
+	ctx           context.Context
+	w             *writer
+	byteThreshold int
+	lineThreshold int
+
+	buf       []byte
+	lineCount int
+	closed    bool
+}
+
+// WriteStream opens a long-lived write stream for raw line-protocol bytes.
+// The returned io.WriteCloser is not safe for concurrent use; callers
+// wanting concurrent streams should open one per goroutine. Close flushes
+// any buffered bytes and must be called to release the stream.
+func (w *WriteAPIImpl) WriteStream(ctx context.Context) (io.WriteCloser, error) {
+	return w.writer.newWriteStream(ctx), nil
+}
+
+// newWriteStream builds a writeStream writing through w, shared by
+// WriteAPIImpl.WriteStream and WriteAPIBlockingImpl.WriteReader.
+func (w *writer) newWriteStream(ctx context.Context) *writeStream {
+	return &writeStream{
+		ctx:           ctx,
+		w:             w,
+		byteThreshold: DefaultWriteStreamByteThreshold,
+		lineThreshold: int(w.writeOptions.BatchSize()),
+	}
+}
+
+// Write implements io.Writer. It buffers p and transparently flushes the
+// current chunk to the server once the byte or line-count threshold is hit.
+// A flush only ever sends whole lines: if the threshold is crossed
+// mid-line, the incomplete trailing line is kept buffered until its
+// terminating '\n' arrives, so a chunk boundary never splits a point.
+func (s *writeStream) Write(p []byte) (int, error) {
+	if s.closed {
+		return 0, io.ErrClosedPipe
+	}
+	s.buf = append(s.buf, p...)
+	s.lineCount += bytes.Count(p, []byte{'\n'})
+	if len(s.buf) >= s.byteThreshold || (s.lineThreshold > 0 && s.lineCount >= s.lineThreshold) {
+		if err := s.Flush(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush blocks until every complete line currently buffered has been sent
+// and acknowledged by the server. It is a no-op if there is nothing
+// buffered. Any trailing bytes not yet terminated by '\n' are left in the
+// buffer for the next Write or the final Close.
+func (s *writeStream) Flush() error {
+	cut := bytes.LastIndexByte(s.buf, '\n') + 1
+	if cut == 0 {
+		return nil
+	}
+	if err := s.w.writeRaw(s.ctx, s.buf[:cut]); err != nil {
+		return err
+	}
+	remaining := len(s.buf) - cut
+	copy(s.buf, s.buf[cut:])
+	s.buf = s.buf[:remaining]
+	s.lineCount = bytes.Count(s.buf, []byte{'\n'})
+	return nil
+}
+
+// Close flushes any remaining buffered bytes, including a final line that
+// wasn't terminated with '\n', and closes the stream.
+func (s *writeStream) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if err := s.Flush(); err != nil {
+		return err
+	}
+	if len(s.buf) == 0 {
+		return nil
+	}
+	if err := s.w.writeRaw(s.ctx, s.buf); err != nil {
+		return err
+	}
+	s.buf = s.buf[:0]
+	return nil
+}
+
+// WriteReader reads line protocol from r until EOF and writes it to the
+// server, blocking until every point has been acknowledged. It is the
+// WriteAPIBlocking counterpart of WriteAPIImpl.WriteStream, for callers
+// porting raw-bytes ingest pipelines (log shippers, ETL jobs, tsbs-style
+// benchmarks) that don't want to go through Point allocation.
+func (w *WriteAPIBlockingImpl) WriteReader(ctx context.Context, r io.Reader) error {
+	stream := w.writer.newWriteStream(ctx)
+	if _, err := io.Copy(stream, r); err != nil {
+		_ = stream.Close()
+		return err
+	}
+	return stream.Close()
+}
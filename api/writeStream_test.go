@@ -0,0 +1,92 @@
+// Copyright 2020-2021 InfluxData, Inc. All rights reserved.
+// Use of this source code is governed by MIT
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+// fakeWriteService is a minimal ihttp.Service that records every body it
+// was asked to write, for exercising WriteStream/WriteReader's chunking
+// without a real server.
+type fakeWriteService struct {
+	serverURL     string
+	authorization string
+	chunks        [][]byte
+}
+
+func newFakeWriteService() *fakeWriteService {
+	return &fakeWriteService{serverURL: "http://fake/"}
+}
+
+func (s *fakeWriteService) ServerURL() string    { return s.serverURL }
+func (s *fakeWriteService) ServerAPIURL() string { return s.serverURL + "api/v2/" }
+func (s *fakeWriteService) SetAuthorization(authorization string) {
+	s.authorization = authorization
+}
+
+func (s *fakeWriteService) Do(req *http.Request) (*http.Response, error) {
+	var data []byte
+	if req.Body != nil {
+		var err error
+		data, err = ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+	}
+	s.chunks = append(s.chunks, data)
+	return &http.Response{StatusCode: http.StatusNoContent, Body: ioutil.NopCloser(bytes.NewReader(nil)), Header: make(http.Header)}, nil
+}
+
+func TestWriteStreamOnlyFlushesWholeLines(t *testing.T) {
+	svc := newFakeWriteService()
+	w := NewWriteAPI("my-org", "my-bucket", svc, DefaultWriteOptions().SetBatchSize(0))
+	stream, err := w.WriteStream(context.Background())
+	if err != nil {
+		t.Fatalf("WriteStream: %v", err)
+	}
+	s := stream.(*writeStream)
+	s.byteThreshold = 10 // force a rotation well before a full line is buffered
+
+	if _, err := stream.Write([]byte("cpu,host=a value=1\ncpu,host=b valu")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if len(svc.chunks) != 1 {
+		t.Fatalf("expected exactly one flushed chunk once the threshold was crossed mid-line, got %d", len(svc.chunks))
+	}
+	if got := string(svc.chunks[0]); got != "cpu,host=a value=1\n" {
+		t.Fatalf("flush should only ship the complete line, got %q", got)
+	}
+
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if len(svc.chunks) != 2 {
+		t.Fatalf("expected Close to flush the trailing unterminated line, got %d chunks", len(svc.chunks))
+	}
+	if got := string(svc.chunks[1]); got != "cpu,host=b valu" {
+		t.Fatalf("Close should flush the remaining partial line, got %q", got)
+	}
+}
+
+func TestWriteReaderWritesEveryLine(t *testing.T) {
+	svc := newFakeWriteService()
+	wb := NewWriteAPIBlocking("my-org", "my-bucket", svc, nil)
+	r := bytes.NewReader([]byte("cpu,host=a value=1\ncpu,host=b value=2\n"))
+	if err := wb.WriteReader(context.Background(), r); err != nil {
+		t.Fatalf("WriteReader: %v", err)
+	}
+	var all bytes.Buffer
+	for _, c := range svc.chunks {
+		all.Write(c)
+	}
+	if all.String() != "cpu,host=a value=1\ncpu,host=b value=2\n" {
+		t.Fatalf("expected every line to reach the service, got %q", all.String())
+	}
+}
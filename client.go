@@ -49,11 +49,25 @@ type Client interface {
 	// WriteAPIBlocking returns the synchronous, blocking, Write client.
 	// Ensures using a single WriteAPIBlocking instance for each org/bucket pair.
 	WriteAPIBlocking(org, bucket string) api.WriteAPIBlocking
+	// WriteAPIV1 returns the asynchronous, non-blocking, Write client addressed
+	// by InfluxDB 1.8 database and retention policy, for use with a Client
+	// created by NewClientV1.
+	WriteAPIV1(database, retentionPolicy string) api.WriteAPI
+	// WriteAPIBlockingV1 returns the synchronous, blocking, Write client addressed
+	// by InfluxDB 1.8 database and retention policy, for use with a Client
+	// created by NewClientV1.
+	WriteAPIBlockingV1(database, retentionPolicy string) api.WriteAPIBlocking
+	// InfluxQLQueryAPI returns a client for running InfluxQL queries against
+	// database using InfluxDB's legacy /query endpoint.
+	InfluxQLQueryAPI(database string) api.InfluxQLQueryAPI
 	// QueryAPI returns Query client.
 	// Ensures using a single QueryAPI instance each org.
 	QueryAPI(org string) api.QueryAPI
 	// AuthorizationsAPI returns Authorizations API client.
 	AuthorizationsAPI() api.AuthorizationsAPI
+	// V1AuthorizationsAPI returns API client for managing InfluxDB 1.x
+	// compatible (username/password) authorizations.
+	V1AuthorizationsAPI() api.V1AuthorizationsAPI
 	// OrganizationsAPI returns Organizations API client
 	OrganizationsAPI() api.OrganizationsAPI
 	// UsersAPI returns Users API client.
@@ -78,6 +92,7 @@ type clientImpl struct {
 	httpService   http.Service
 	apiClient     *domain.ClientWithResponses
 	authAPI       api.AuthorizationsAPI
+	v1AuthAPI     api.V1AuthorizationsAPI
 	orgAPI        api.OrganizationsAPI
 	usersAPI      api.UsersAPI
 	deleteAPI     api.DeleteAPI
@@ -100,15 +115,26 @@ func NewClient(serverURL string, authToken string) Client {
 // authToken is an authentication token. It can be empty in case of connecting to newly installed InfluxDB server, which has not been set up yet.
 // In such case, calling Setup() will set authentication token
 func NewClientWithOptions(serverURL string, authToken string, options *Options) Client {
+	authorization := ""
+	if len(authToken) > 0 {
+		authorization = "Token " + authToken
+	}
+	client := newClientImpl(serverURL, authorization, options)
+	ilog.Infof("Using URL '%s', token '%s'", serverURL, authToken)
+	return client
+}
+
+// newClientImpl builds the clientImpl shared by every Client constructor:
+// it normalizes serverURL, sets up the HTTP and domain API clients with
+// authorization, and applies options.LogLevel() to the package-wide logger.
+// authorization is the full Authorization header value, e.g. "Token ..."
+// or "Basic ...", and may be empty.
+func newClientImpl(serverURL, authorization string, options *Options) *clientImpl {
 	normServerURL := serverURL
 	if !strings.HasSuffix(normServerURL, "/") {
 		// For subsequent path parts concatenation, url has to end with '/'
 		normServerURL = serverURL + "/"
 	}
-	authorization := ""
-	if len(authToken) > 0 {
-		authorization = "Token " + authToken
-	}
 	service := http.NewService(normServerURL, authorization, options.httpOptions)
 	client := &clientImpl{
 		serverURL:     serverURL,
@@ -121,7 +147,6 @@ func NewClientWithOptions(serverURL string, authToken string, options *Options)
 	if log.Log != nil {
 		log.Log.SetLogLevel(options.LogLevel())
 	}
-	ilog.Infof("Using URL '%s', token '%s'", serverURL, authToken)
 	return client
 }
 func (c *clientImpl) Options() *Options {
@@ -246,6 +271,15 @@ func (c *clientImpl) AuthorizationsAPI() api.AuthorizationsAPI {
 	return c.authAPI
 }
 
+func (c *clientImpl) V1AuthorizationsAPI() api.V1AuthorizationsAPI {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.v1AuthAPI == nil {
+		c.v1AuthAPI = api.NewV1AuthorizationsAPI(c.apiClient)
+	}
+	return c.v1AuthAPI
+}
+
 func (c *clientImpl) OrganizationsAPI() api.OrganizationsAPI {
 	c.lock.Lock()
 	defer c.lock.Unlock()
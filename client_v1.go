@@ -0,0 +1,62 @@
+// Copyright 2020-2021 InfluxData, Inc. All rights reserved.
+// Use of this source code is governed by MIT
+// license that can be found in the LICENSE file.
+
+package influxdb2
+
+import (
+	"fmt"
+
+	"github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/api/http"
+)
+
+// NewClientV1 creates a Client that talks to an InfluxDB 1.8+ server using its
+// v2-compatible API with username/password (Basic) authentication instead of
+// a token. The returned Client can be used exactly like one created with
+// NewClient, except that WriteAPI/WriteAPIBlocking must be given a bucket
+// string built with V1Bucket, e.g. client.WriteAPI("", influxdb2.V1Bucket("telegraf", "autogen")),
+// or obtained directly via WriteAPIV1/WriteAPIBlockingV1.
+// If opts is nil, DefaultOptions are used.
+func NewClientV1(serverURL, username, password string, opts *Options) Client {
+	if opts == nil {
+		opts = DefaultOptions()
+	}
+	authorization := ""
+	if len(username) > 0 || len(password) > 0 {
+		authorization = "Basic " + http.BasicAuth(username, password)
+	}
+	return newClientImpl(serverURL, authorization, opts)
+}
+
+// V1Bucket returns the "<database>/<retentionPolicy>" bucket identifier that
+// InfluxDB 1.8's v2-compatible write and query endpoints expect in place of a
+// bucket name. retentionPolicy may be empty, in which case the server's
+// default retention policy for database is used.
+func V1Bucket(database, retentionPolicy string) string {
+	if retentionPolicy == "" {
+		return database
+	}
+	return fmt.Sprintf("%s/%s", database, retentionPolicy)
+}
+
+// WriteAPIV1 returns the asynchronous, non-blocking, write client for the
+// given database and retentionPolicy. It is a convenience wrapper around
+// WriteAPI("", V1Bucket(database, retentionPolicy)) for use against InfluxDB 1.8.
+func (c *clientImpl) WriteAPIV1(database, retentionPolicy string) api.WriteAPI {
+	return c.WriteAPI("", V1Bucket(database, retentionPolicy))
+}
+
+// WriteAPIBlockingV1 returns the synchronous, blocking, write client for the
+// given database and retentionPolicy. It is a convenience wrapper around
+// WriteAPIBlocking("", V1Bucket(database, retentionPolicy)) for use against InfluxDB 1.8.
+func (c *clientImpl) WriteAPIBlockingV1(database, retentionPolicy string) api.WriteAPIBlocking {
+	return c.WriteAPIBlocking("", V1Bucket(database, retentionPolicy))
+}
+
+// InfluxQLQueryAPI returns an API client for running InfluxQL queries against
+// the given database using InfluxDB's legacy /query endpoint. It is available
+// on both InfluxDB 1.8+ and 2.x servers running in 1.x compatibility mode.
+func (c *clientImpl) InfluxQLQueryAPI(database string) api.InfluxQLQueryAPI {
+	return api.NewInfluxQLQueryAPI(database, c.httpService)
+}
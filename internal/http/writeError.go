@@ -0,0 +1,84 @@
+// Copyright 2020-2021 InfluxData, Inc. All rights reserved.
+// Use of this source code is governed by MIT
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	apierrors "github.com/influxdata/influxdb-client-go/v2/api/errors"
+)
+
+// errorBody mirrors the JSON body InfluxDB returns for a failed write,
+// e.g. {"code":"invalid","message":"partial write: unable to parse 'cpu,: EOF unable to parse ... dropped=1"}.
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+var (
+	lineColumnRe = regexp.MustCompile(`line (\d+)(?:, column (\d+))?`)
+	dropReasonRe = regexp.MustCompile(`dropped=\d+;\s*(.*)$`)
+)
+
+// ParseWriteError classifies a failed write response into one of the typed
+// errors in api/errors, falling back to a generic APIError when the body
+// doesn't match any of the well-known InfluxDB write failure messages.
+// bucket is the bucket or "db/rp" the write targeted, used to populate
+// DatabaseNotFoundError.
+func ParseWriteError(statusCode int, bucket string, body []byte) error {
+	var eb errorBody
+	_ = json.Unmarshal(body, &eb)
+	message := eb.Message
+	if message == "" {
+		message = string(body)
+	}
+	lower := strings.ToLower(message)
+
+	switch {
+	case strings.Contains(lower, "database not found"):
+		return &apierrors.DatabaseNotFoundError{Bucket: bucket}
+	case strings.Contains(lower, "points beyond retention policy"):
+		return &apierrors.PointsBeyondRetentionPolicyError{Reason: message}
+	case strings.Contains(lower, "unable to parse"):
+		// Checked before "partial write": a partial write caused by a parse
+		// error carries both substrings (e.g. "partial write: unable to
+		// parse 'cpu,: EOF ... dropped=1"), and UnableToParseError's
+		// line/column is the more actionable classification of the two.
+		line, column := extractLineColumn(message)
+		return &apierrors.UnableToParseError{Line: line, Column: column, Reason: message}
+	case strings.Contains(lower, "partial write"):
+		return &apierrors.PartialWriteError{Line: extractLine(message), Reason: extractReason(message)}
+	default:
+		return &apierrors.APIError{StatusCode: statusCode, Code: eb.Code, Message: message}
+	}
+}
+
+func extractLineColumn(message string) (line, column int) {
+	m := lineColumnRe.FindStringSubmatch(message)
+	if m == nil {
+		return -1, -1
+	}
+	line, _ = strconv.Atoi(m[1])
+	column = -1
+	if m[2] != "" {
+		column, _ = strconv.Atoi(m[2])
+	}
+	return line, column
+}
+
+func extractLine(message string) int {
+	line, _ := extractLineColumn(message)
+	return line
+}
+
+func extractReason(message string) string {
+	if m := dropReasonRe.FindStringSubmatch(message); m != nil {
+		return m[1]
+	}
+	return message
+}
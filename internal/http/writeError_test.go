@@ -0,0 +1,77 @@
+// Copyright 2020-2021 InfluxData, Inc. All rights reserved.
+// Use of this source code is governed by MIT
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"testing"
+
+	apierrors "github.com/influxdata/influxdb-client-go/v2/api/errors"
+)
+
+func TestParseWriteErrorClassifiesKnownMessages(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want interface{}
+	}{
+		{
+			name: "database not found",
+			body: `{"code":"not found","message":"database not found: \"telegraf\""}`,
+			want: &apierrors.DatabaseNotFoundError{},
+		},
+		{
+			name: "points beyond retention policy",
+			body: `{"code":"invalid","message":"points beyond retention policy"}`,
+			want: &apierrors.PointsBeyondRetentionPolicyError{},
+		},
+		{
+			name: "partial write caused by a parse error classifies as UnableToParseError",
+			body: `{"code":"invalid","message":"partial write: unable to parse 'cpu,: EOF unable to parse 'cpu,': invalid line, line 3, column 5 dropped=1"}`,
+			want: &apierrors.UnableToParseError{},
+		},
+		{
+			name: "plain partial write",
+			body: `{"code":"invalid","message":"partial write: field type conflict dropped=2; field type conflict"}`,
+			want: &apierrors.PartialWriteError{},
+		},
+		{
+			name: "unknown body falls back to APIError",
+			body: `{"code":"internal error","message":"something else broke"}`,
+			want: &apierrors.APIError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ParseWriteError(400, "telegraf/autogen", []byte(tt.body))
+			switch tt.want.(type) {
+			case *apierrors.DatabaseNotFoundError:
+				if _, ok := err.(*apierrors.DatabaseNotFoundError); !ok {
+					t.Fatalf("expected *DatabaseNotFoundError, got %T: %v", err, err)
+				}
+			case *apierrors.PointsBeyondRetentionPolicyError:
+				if _, ok := err.(*apierrors.PointsBeyondRetentionPolicyError); !ok {
+					t.Fatalf("expected *PointsBeyondRetentionPolicyError, got %T: %v", err, err)
+				}
+			case *apierrors.UnableToParseError:
+				parseErr, ok := err.(*apierrors.UnableToParseError)
+				if !ok {
+					t.Fatalf("expected *UnableToParseError, got %T: %v", err, err)
+				}
+				if parseErr.Line != 3 || parseErr.Column != 5 {
+					t.Fatalf("expected line 3 column 5, got line %d column %d", parseErr.Line, parseErr.Column)
+				}
+			case *apierrors.PartialWriteError:
+				if _, ok := err.(*apierrors.PartialWriteError); !ok {
+					t.Fatalf("expected *PartialWriteError, got %T: %v", err, err)
+				}
+			case *apierrors.APIError:
+				if _, ok := err.(*apierrors.APIError); !ok {
+					t.Fatalf("expected *APIError, got %T: %v", err, err)
+				}
+			}
+		})
+	}
+}
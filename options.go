@@ -0,0 +1,62 @@
+// Copyright 2020-2021 InfluxData, Inc. All rights reserved.
+// Use of this source code is governed by MIT
+// license that can be found in the LICENSE file.
+
+package influxdb2
+
+import (
+	"net/http"
+
+	"github.com/influxdata/influxdb-client-go/v2/api"
+	ihttp "github.com/influxdata/influxdb-client-go/v2/api/http"
+)
+
+// Options holds the configuration for a Client.
+type Options struct {
+	httpOptions  *ihttp.Options
+	writeOptions *api.WriteOptions
+	logLevel     uint
+}
+
+// DefaultOptions returns Options initialised with the client's defaults.
+func DefaultOptions() *Options {
+	return &Options{
+		httpOptions:  ihttp.DefaultOptions(),
+		writeOptions: api.DefaultWriteOptions(),
+	}
+}
+
+// HTTPOptions returns the options governing the underlying HTTP transport.
+func (o *Options) HTTPOptions() *ihttp.Options {
+	return o.httpOptions
+}
+
+// HTTPClient returns the *http.Client used for requests.
+func (o *Options) HTTPClient() *http.Client {
+	return o.httpOptions.HTTPClient()
+}
+
+// WriteOptions returns the options governing WriteAPI and WriteAPIBlocking,
+// including the RetryPolicy and CircuitBreaker consulted on every write.
+func (o *Options) WriteOptions() *api.WriteOptions {
+	return o.writeOptions
+}
+
+// SetWriteOptions replaces the options governing WriteAPI and
+// WriteAPIBlocking. It has no effect on WriteAPI/WriteAPIBlocking instances
+// already handed out by Client.
+func (o *Options) SetWriteOptions(writeOptions *api.WriteOptions) *Options {
+	o.writeOptions = writeOptions
+	return o
+}
+
+// LogLevel returns the configured log verbosity.
+func (o *Options) LogLevel() uint {
+	return o.logLevel
+}
+
+// SetLogLevel sets the log verbosity and returns o for chaining.
+func (o *Options) SetLogLevel(logLevel uint) *Options {
+	o.logLevel = logLevel
+	return o
+}